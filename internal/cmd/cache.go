@@ -0,0 +1,23 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// resolverCacheDir returns the directory a sourcemap.Resolver should
+// cache resolved contract source under, so commands that build one with
+// it share the same on-disk cache instead of each starting cold; stats
+// uses it for its Resolver. Falls back to the OS temp dir if the user cache dir
+// can't be determined, mirroring how Resolver itself degrades to
+// "caching disabled" rather than failing outright.
+func resolverCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "erst")
+	}
+	return filepath.Join(dir, "erst")
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flamegraphSessionFlag string
+	flamegraphFormatFlag  string
+)
+
+var flamegraphCmd = &cobra.Command{
+	Use:   "flamegraph",
+	Short: "Export the decoded call tree as a flamegraph-ready file",
+	Long: `Exports the reconstructed contract call tree in a format suitable for
+flame graph tooling: "folded" (Brendan Gregg's flamegraph.pl collapsed-stack
+text) or "speedscope" (the speedscope.app evented profile JSON). Frames are
+weighted by estimated fee from the resource model when available, falling
+back to event count. By default fees are priced against the built-in
+protocol approximation; pass --live to price against the network's
+actual config settings instead, which costs an RPC round trip.`,
+	Args: cobra.NoArgs,
+	RunE: runFlamegraph,
+}
+
+func runFlamegraph(cmd *cobra.Command, args []string) error {
+	simResp, err := loadSimulationResponse(cmd, flamegraphSessionFlag)
+	if err != nil {
+		return err
+	}
+
+	root, err := decoder.DecodeEvents(simResp.RawEvents)
+	if err != nil {
+		return fmt.Errorf("failed to decode call tree: %w", err)
+	}
+
+	schedule := resolveFeeSchedule(cmd.Context())
+	weigher := feeWeigher(root, simResp, schedule)
+
+	return decoder.Export(root, flamegraphFormatFlag, os.Stdout, decoder.WithWeigher(weigher))
+}
+
+// feeWeigher estimates each contract's resource usage the same way
+// `stats` does (see resources.go), then returns a decoder.FrameWeigher
+// that spreads each contract's estimated fee across its own frames in
+// proportion to their share of that contract's events. Nodes with no
+// resolvable contract ID fall back to a weight of 1 per event, as does
+// every node when resp carries no transaction data.
+func feeWeigher(root *decoder.CallNode, resp *simulator.SimulationResponse, schedule FeeSchedule) decoder.FrameWeigher {
+	if resp.TransactionData == nil {
+		return func(node *decoder.CallNode) uint64 { return uint64(len(node.Events)) }
+	}
+
+	eventCounts := make(map[string]int)
+	var count func(node *decoder.CallNode)
+	count = func(node *decoder.CallNode) {
+		if node.ContractID != nil && *node.ContractID != "" {
+			eventCounts[*node.ContractID] += len(node.Events)
+		}
+		for _, child := range node.SubCalls {
+			count(child)
+		}
+	}
+	count(root)
+
+	total := resourceUsageFromTransactionData(resp.TransactionData, uint64(len(resp.DiagnosticEvents)), uint64(len(resp.EnvelopeXdr)))
+	perContract := shareAcrossContracts(total, eventCounts)
+
+	contractFee := make(map[string]uint64, len(perContract))
+	for id, usage := range perContract {
+		contractFee[id] = estimatedFeeStroops(usage, schedule)
+	}
+
+	return func(node *decoder.CallNode) uint64 {
+		if node.ContractID == nil || *node.ContractID == "" {
+			return uint64(len(node.Events))
+		}
+		id := *node.ContractID
+		total, ok := eventCounts[id]
+		if !ok || total == 0 {
+			return uint64(len(node.Events))
+		}
+		return contractFee[id] * uint64(len(node.Events)) / uint64(total)
+	}
+}
+
+func init() {
+	flamegraphCmd.Flags().StringVar(&flamegraphSessionFlag, "session", "", "Load a saved session by ID")
+	flamegraphCmd.Flags().StringVar(&flamegraphFormatFlag, "format", "folded", "Export format: folded or speedscope")
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+)
+
+// networkFlag, rpcURLFlag, and liveFlag back the `--network` /
+// `--rpc-url` / `--live` flags. Their reader is resolveFeeSchedule,
+// which stats and flamegraph call to reach a live Soroban-RPC node when
+// --live is set. They are registered as persistent flags on the root
+// command so any subcommand can read them.
+var (
+	networkFlag string
+	rpcURLFlag  string
+	liveFlag    bool
+)
+
+// resolveNetwork maps networkFlag to its rpc.Network, applying an
+// rpcURLFlag override if one was supplied.
+func resolveNetwork() (rpc.Network, error) {
+	var network rpc.Network
+	switch networkFlag {
+	case "", "public", "mainnet":
+		network = rpc.NetworkPublic
+	case "testnet":
+		network = rpc.NetworkTestnet
+	case "futurenet":
+		network = rpc.NetworkFuturenet
+	default:
+		return rpc.Network{}, fmt.Errorf("unknown network %q (want public, testnet, or futurenet)", networkFlag)
+	}
+
+	if rpcURLFlag != "" {
+		network.RPCURL = rpcURLFlag
+	}
+	return network, nil
+}
+
+// newRPCClient builds the rpc.Client a command should use, honoring
+// `--network` / `--rpc-url`.
+func newRPCClient() (*rpc.Client, error) {
+	network, err := resolveNetwork()
+	if err != nil {
+		return nil, err
+	}
+	return rpc.NewClientForNetwork(network), nil
+}
+
+// resolveFeeSchedule returns the FeeSchedule a resource-estimating
+// command should price against. It only reaches out to a live
+// Soroban-RPC node when `--live` was passed; otherwise it uses
+// defaultFeeSchedule directly so commands working from a saved session
+// (e.g. `stats --session`) don't eat RPC latency they didn't ask for.
+func resolveFeeSchedule(ctx context.Context) FeeSchedule {
+	if !liveFlag {
+		return defaultFeeSchedule()
+	}
+
+	client, err := newRPCClient()
+	if err != nil {
+		logger.Logger.Debug("Falling back to default fee schedule: failed to build RPC client", "error", err)
+		return defaultFeeSchedule()
+	}
+	return loadFeeSchedule(ctx, client)
+}
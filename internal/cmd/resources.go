@@ -0,0 +1,200 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stellar/go/xdr"
+)
+
+// ResourceUsage is a Soroban resource accounting, i.e. the dimensions
+// the network actually bills for instead of the call/storage/auth event
+// counts the old weighted score used.
+type ResourceUsage struct {
+	CPUInstructions uint64
+	// MemoryBytes is left at zero: Soroban doesn't meter memory
+	// separately from CPU instructions at the transaction level today,
+	// so there is nothing honest to report here yet.
+	MemoryBytes  uint64
+	ReadBytes    uint64
+	WriteBytes   uint64
+	ReadEntries  uint32
+	WriteEntries uint32
+	EventsBytes  uint64
+	TxSizeBytes  uint64
+}
+
+// FeeSchedule is the per-unit stroop cost for each ResourceUsage
+// dimension. The CPU and ledger read/write rates come from the
+// network's config settings ledger entries when reachable (see
+// loadFeeSchedule); the remaining rates, and all of them when the
+// network is unreachable, are approximate protocol 20 defaults so
+// `stats` still works offline.
+type FeeSchedule struct {
+	PerCPUInstruction uint64
+	PerReadByte       uint64
+	PerWriteByte      uint64
+	PerReadEntry      uint64
+	PerWriteEntry     uint64
+	PerEventByte      uint64
+	PerTxByte         uint64
+}
+
+// defaultFeeSchedule approximates the published protocol 20 resource
+// fee rates (stroops per unit). It is intentionally coarse: it exists
+// so `stats` degrades gracefully without a live RPC connection, not as
+// a source of truth for fee estimation.
+func defaultFeeSchedule() FeeSchedule {
+	return FeeSchedule{
+		PerCPUInstruction: 1,
+		PerReadByte:       1,
+		PerWriteByte:      1,
+		PerReadEntry:      1000,
+		PerWriteEntry:     5000,
+		PerEventByte:      1,
+		PerTxByte:         1,
+	}
+}
+
+// loadFeeSchedule fetches the live contractComputeV0 and
+// contractLedgerCostV0 config settings from the network and converts
+// them into a FeeSchedule. The contractEventsV0 and contractBandwidthV0
+// settings are not fetched, so PerEventByte and PerTxByte always keep
+// their defaultFeeSchedule values. It falls back to
+// defaultFeeSchedule on any error so a flaky RPC node degrades `stats`
+// rather than breaking it.
+func loadFeeSchedule(ctx context.Context, client *rpc.Client) FeeSchedule {
+	schedule := defaultFeeSchedule()
+	if client == nil || client.Soroban == nil {
+		return schedule
+	}
+
+	compute, err := client.Soroban.GetNetworkConfig(ctx, rpc.ConfigSettingContractComputeV0)
+	if err != nil {
+		logger.Logger.Debug("Falling back to default fee schedule: compute config unavailable", "error", err)
+		return schedule
+	}
+	ledgerCost, err := client.Soroban.GetNetworkConfig(ctx, rpc.ConfigSettingContractLedgerCostV0)
+	if err != nil {
+		logger.Logger.Debug("Falling back to default fee schedule: ledger cost config unavailable", "error", err)
+		return schedule
+	}
+
+	applyComputeFees(&schedule, compute)
+	applyLedgerCostFees(&schedule, ledgerCost)
+	return schedule
+}
+
+func applyComputeFees(schedule *FeeSchedule, entry *rpc.LedgerEntryResult) {
+	var le xdr.LedgerEntryData
+	if err := xdr.SafeUnmarshalBase64(entry.XDR, &le); err != nil {
+		logger.Logger.Debug("Failed to decode contractComputeV0 config setting", "error", err)
+		return
+	}
+	cfg := le.ConfigSetting
+	if cfg == nil || cfg.ContractCostParamsCpuInsns == nil {
+		return
+	}
+	// The first cost param entry is the linear fee rate per instruction
+	// for the baseline "invoke" cost type.
+	params := *cfg.ContractCostParamsCpuInsns
+	if len(params) > 0 {
+		schedule.PerCPUInstruction = uint64(params[0].LinearTerm)
+	}
+}
+
+func applyLedgerCostFees(schedule *FeeSchedule, entry *rpc.LedgerEntryResult) {
+	var le xdr.LedgerEntryData
+	if err := xdr.SafeUnmarshalBase64(entry.XDR, &le); err != nil {
+		logger.Logger.Debug("Failed to decode contractLedgerCostV0 config setting", "error", err)
+		return
+	}
+	cfg := le.ConfigSetting
+	if cfg == nil || cfg.ContractLedgerCost == nil {
+		return
+	}
+	lc := cfg.ContractLedgerCost
+	schedule.PerReadByte = ceilDivUint64(uint64(lc.FeeRead1Kb), 1024)
+	schedule.PerWriteByte = ceilDivUint64(uint64(lc.FeeWrite1Kb), 1024)
+	schedule.PerReadEntry = uint64(lc.FeeReadLedgerEntry)
+	schedule.PerWriteEntry = uint64(lc.FeeWriteLedgerEntry)
+}
+
+// ceilDivUint64 divides a by b, rounding up. FeeRead1Kb/FeeWrite1Kb are
+// priced per KB and are routinely under 1024 stroops at current network
+// rates, so a plain integer division truncates the per-byte rate to
+// zero -- rounding up keeps a non-zero (if slightly overestimated)
+// per-byte term instead of silently dropping it from the fee estimate.
+func ceilDivUint64(a, b uint64) uint64 {
+	if a == 0 {
+		return 0
+	}
+	return (a + b - 1) / b
+}
+
+// resourceUsageFromTransactionData converts the SorobanTransactionData
+// already present in a fetched transaction's ResultMetaXdr into a
+// ResourceUsage. It is transaction-wide by construction: Soroban bills
+// resources once per transaction, not per contract call.
+func resourceUsageFromTransactionData(data *xdr.SorobanTransactionData, eventsBytes, txSizeBytes uint64) ResourceUsage {
+	if data == nil {
+		return ResourceUsage{}
+	}
+
+	res := data.Resources
+	return ResourceUsage{
+		CPUInstructions: uint64(res.Instructions),
+		ReadBytes:       uint64(res.ReadBytes),
+		WriteBytes:      uint64(res.WriteBytes),
+		ReadEntries:     uint32(len(res.Footprint.ReadOnly)),
+		WriteEntries:    uint32(len(res.Footprint.ReadWrite)),
+		EventsBytes:     eventsBytes,
+		TxSizeBytes:     txSizeBytes,
+	}
+}
+
+// estimatedFeeStroops prices usage under schedule.
+func estimatedFeeStroops(usage ResourceUsage, schedule FeeSchedule) uint64 {
+	return usage.CPUInstructions*schedule.PerCPUInstruction +
+		usage.ReadBytes*schedule.PerReadByte +
+		usage.WriteBytes*schedule.PerWriteByte +
+		uint64(usage.ReadEntries)*schedule.PerReadEntry +
+		uint64(usage.WriteEntries)*schedule.PerWriteEntry +
+		usage.EventsBytes*schedule.PerEventByte +
+		usage.TxSizeBytes*schedule.PerTxByte
+}
+
+// shareAcrossContracts splits a transaction-wide ResourceUsage across
+// contracts proportionally to each contract's share of total events.
+// This is an estimate, not a real per-contract metering result: Soroban
+// does not attribute CPU/IO cost to individual calls within a
+// transaction, so event share is the closest proxy available from the
+// data `stats` already has.
+func shareAcrossContracts(total ResourceUsage, eventCounts map[string]int) map[string]ResourceUsage {
+	totalEvents := 0
+	for _, n := range eventCounts {
+		totalEvents += n
+	}
+	if totalEvents == 0 {
+		return nil
+	}
+
+	out := make(map[string]ResourceUsage, len(eventCounts))
+	for id, n := range eventCounts {
+		share := float64(n) / float64(totalEvents)
+		out[id] = ResourceUsage{
+			CPUInstructions: uint64(float64(total.CPUInstructions) * share),
+			ReadBytes:       uint64(float64(total.ReadBytes) * share),
+			WriteBytes:      uint64(float64(total.WriteBytes) * share),
+			ReadEntries:     uint32(float64(total.ReadEntries) * share),
+			WriteEntries:    uint32(float64(total.WriteEntries) * share),
+			EventsBytes:     uint64(float64(total.EventsBytes) * share),
+			TxSizeBytes:     uint64(float64(total.TxSizeBytes) * share),
+		}
+	}
+	return out
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ledgerEntryResult marshals data as the base64 XDR a getLedgerEntries
+// response would carry, matching what applyComputeFees/applyLedgerCostFees
+// decode via xdr.SafeUnmarshalBase64.
+func ledgerEntryResult(t *testing.T, data xdr.LedgerEntryData) *rpc.LedgerEntryResult {
+	b64, err := xdr.MarshalBase64(data)
+	require.NoError(t, err)
+	return &rpc.LedgerEntryResult{XDR: b64}
+}
+
+func TestResourceUsageFromTransactionDataNilIsZeroValue(t *testing.T) {
+	usage := resourceUsageFromTransactionData(nil, 10, 20)
+	assert.Equal(t, ResourceUsage{}, usage)
+}
+
+func TestResourceUsageFromTransactionData(t *testing.T) {
+	data := &xdr.SorobanTransactionData{
+		Resources: xdr.SorobanResources{
+			Footprint: xdr.LedgerFootprint{
+				ReadOnly:  []xdr.LedgerKey{{}, {}},
+				ReadWrite: []xdr.LedgerKey{{}},
+			},
+			Instructions: 500,
+			ReadBytes:    1024,
+			WriteBytes:   256,
+		},
+	}
+
+	usage := resourceUsageFromTransactionData(data, 64, 128)
+	assert.EqualValues(t, 500, usage.CPUInstructions)
+	assert.EqualValues(t, 1024, usage.ReadBytes)
+	assert.EqualValues(t, 256, usage.WriteBytes)
+	assert.EqualValues(t, 2, usage.ReadEntries)
+	assert.EqualValues(t, 1, usage.WriteEntries)
+	assert.EqualValues(t, 64, usage.EventsBytes)
+	assert.EqualValues(t, 128, usage.TxSizeBytes)
+}
+
+func TestEstimatedFeeStroops(t *testing.T) {
+	usage := ResourceUsage{
+		CPUInstructions: 10,
+		ReadBytes:       20,
+		WriteBytes:      30,
+		ReadEntries:     2,
+		WriteEntries:    1,
+		EventsBytes:     5,
+		TxSizeBytes:     7,
+	}
+	schedule := FeeSchedule{
+		PerCPUInstruction: 1,
+		PerReadByte:       2,
+		PerWriteByte:      3,
+		PerReadEntry:      100,
+		PerWriteEntry:     200,
+		PerEventByte:      4,
+		PerTxByte:         5,
+	}
+
+	got := estimatedFeeStroops(usage, schedule)
+	want := uint64(10*1 + 20*2 + 30*3 + 2*100 + 1*200 + 5*4 + 7*5)
+	assert.Equal(t, want, got)
+}
+
+func TestShareAcrossContractsSplitsProportionally(t *testing.T) {
+	total := ResourceUsage{
+		CPUInstructions: 100,
+		ReadBytes:       200,
+	}
+	eventCounts := map[string]int{"CA": 3, "CB": 1}
+
+	out := shareAcrossContracts(total, eventCounts)
+	assert.EqualValues(t, 75, out["CA"].CPUInstructions)
+	assert.EqualValues(t, 150, out["CA"].ReadBytes)
+	assert.EqualValues(t, 25, out["CB"].CPUInstructions)
+	assert.EqualValues(t, 50, out["CB"].ReadBytes)
+}
+
+func TestShareAcrossContractsNoEventsReturnsNil(t *testing.T) {
+	out := shareAcrossContracts(ResourceUsage{CPUInstructions: 10}, map[string]int{"CA": 0})
+	assert.Nil(t, out)
+}
+
+func TestApplyComputeFeesReadsLinearTerm(t *testing.T) {
+	entry := ledgerEntryResult(t, xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeConfigSetting,
+		ConfigSetting: &xdr.ConfigSettingEntry{
+			ConfigSettingId: xdr.ConfigSettingIdConfigSettingContractComputeV0,
+			ContractCostParamsCpuInsns: &xdr.ContractCostParams{
+				{ConstTerm: 0, LinearTerm: 42},
+			},
+		},
+	})
+
+	schedule := defaultFeeSchedule()
+	applyComputeFees(&schedule, entry)
+	assert.EqualValues(t, 42, schedule.PerCPUInstruction)
+}
+
+func TestApplyComputeFeesIgnoresMalformedXDR(t *testing.T) {
+	schedule := defaultFeeSchedule()
+	original := schedule
+	applyComputeFees(&schedule, &rpc.LedgerEntryResult{XDR: "not-valid-xdr"})
+	assert.Equal(t, original, schedule)
+}
+
+func TestApplyLedgerCostFeesRoundsUpSubKilobyteRates(t *testing.T) {
+	entry := ledgerEntryResult(t, xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeConfigSetting,
+		ConfigSetting: &xdr.ConfigSettingEntry{
+			ConfigSettingId: xdr.ConfigSettingIdConfigSettingContractLedgerCostV0,
+			ContractLedgerCost: &xdr.ConfigSettingContractLedgerCostV0{
+				FeeRead1Kb:          100,
+				FeeWrite1Kb:         200,
+				FeeReadLedgerEntry:  1000,
+				FeeWriteLedgerEntry: 5000,
+			},
+		},
+	})
+
+	schedule := defaultFeeSchedule()
+	applyLedgerCostFees(&schedule, entry)
+
+	// Plain integer division (100/1024, 200/1024) would truncate both
+	// to 0 and silently zero out the byte-fee term; rounding up must
+	// keep them non-zero.
+	assert.EqualValues(t, 1, schedule.PerReadByte)
+	assert.EqualValues(t, 1, schedule.PerWriteByte)
+	assert.EqualValues(t, 1000, schedule.PerReadEntry)
+	assert.EqualValues(t, 5000, schedule.PerWriteEntry)
+}
+
+func TestCeilDivUint64(t *testing.T) {
+	assert.EqualValues(t, 0, ceilDivUint64(0, 1024))
+	assert.EqualValues(t, 1, ceilDivUint64(1, 1024))
+	assert.EqualValues(t, 1, ceilDivUint64(1024, 1024))
+	assert.EqualValues(t, 2, ceilDivUint64(1025, 1024))
+}
+
+func TestDefaultFeeScheduleIsNonZero(t *testing.T) {
+	schedule := defaultFeeSchedule()
+	assert.NotZero(t, schedule.PerCPUInstruction)
+	assert.NotZero(t, schedule.PerReadEntry)
+	assert.NotZero(t, schedule.PerWriteEntry)
+}
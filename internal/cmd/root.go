@@ -0,0 +1,33 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// rootCmd is the parent every subcommand descends from. Flags shared
+// across subcommands (e.g. --network, --rpc-url) belong on its
+// PersistentFlags, which cobra propagates to every descendant command —
+// registering them on one subcommand's PersistentFlags only reaches
+// that subcommand's own children, not its siblings.
+var rootCmd = &cobra.Command{
+	Use:   "erst",
+	Short: "Inspect and debug Soroban contract invocations",
+}
+
+// Execute runs the root command, dispatching to whichever subcommand
+// the user invoked.
+func Execute() error {
+	return rootCmd.Execute()
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&networkFlag, "network", "public", "Stellar network to use (public, testnet, futurenet)")
+	rootCmd.PersistentFlags().StringVar(&rpcURLFlag, "rpc-url", "", "Override the Soroban-RPC endpoint for the selected network")
+	rootCmd.PersistentFlags().BoolVar(&liveFlag, "live", false, "Price resource usage against the network's live fee schedule instead of built-in defaults")
+
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(flamegraphCmd)
+}
@@ -8,40 +8,53 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/logger"
 	"github.com/dotandev/hintents/internal/session"
 	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/sourcemap"
+	"github.com/dotandev/hintents/internal/visualizer"
 	"github.com/spf13/cobra"
 )
 
-const (
-	statsTopN = 5
-
-	// Ledger resource cost weights for estimating "expensive" calls
-	costWeightStorageWrite = 3
-	costWeightAuth         = 2
-	costWeightDefault      = 1
-)
+const statsTopN = 5
 
 var statsSessionFlag string
+var statsSortByFlag string
 
 type contractStat struct {
-	contractID    string
-	eventCount    int
-	storageWrites int
-	authChecks    int
-	estimatedCost uint64
-	callDepth     int
-	seenTypes     map[string]bool
+	contractID          string
+	eventCount          int
+	storageWrites       int
+	authChecks          int
+	callDepth           int
+	seenTypes           map[string]bool
+	resources           ResourceUsage
+	estimatedFeeStroops uint64
+	// topCall is the contract's most-frequently-invoked function, doc
+	// annotated where available. Populated by annotateTopCalls; left
+	// empty if the call tree couldn't be decoded.
+	topCall string
+}
+
+var statsSortFields = map[string]func(a, b contractStat) bool{
+	"fee":     func(a, b contractStat) bool { return a.estimatedFeeStroops > b.estimatedFeeStroops },
+	"cpu":     func(a, b contractStat) bool { return a.resources.CPUInstructions > b.resources.CPUInstructions },
+	"rdbytes": func(a, b contractStat) bool { return a.resources.ReadBytes > b.resources.ReadBytes },
+	"wrbytes": func(a, b contractStat) bool { return a.resources.WriteBytes > b.resources.WriteBytes },
+	"depth":   func(a, b contractStat) bool { return a.callDepth > b.callDepth },
 }
 
 var statsCmd = &cobra.Command{
 	Use:   "stats",
-	Short: "Summarize budget usage and call depth for the top contract calls",
+	Short: "Summarize resource usage and call depth for the top contract calls",
 	Long: `Returns a non-interactive table of the top 5 most expensive contract calls.
-Cost is estimated based on weighted operations:
-  - Storage Writes: weight 3
-  - Auth Checks: weight 2
-  - Other Events: weight 1`,
+Cost is estimated from Soroban's resource model (CPU instructions, ledger
+read/write bytes and entries, event and transaction size) priced against
+a fee schedule, rather than a fixed per-event-type weight. By default the
+schedule is the built-in protocol approximation; pass --live to price
+against the network's actual config settings instead, which costs an
+RPC round trip.`,
 	Args: cobra.NoArgs,
 	RunE: runStats,
 }
@@ -52,16 +65,81 @@ func runStats(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	stats := buildContractStats(simResp)
+	schedule := resolveFeeSchedule(cmd.Context())
+
+	sortBy, ok := statsSortFields[statsSortByFlag]
+	if !ok {
+		return fmt.Errorf("unknown --sort-by %q (want fee, cpu, rdbytes, wrbytes, or depth)", statsSortByFlag)
+	}
+
+	stats := buildContractStats(simResp, schedule, sortBy)
 	if len(stats) == 0 {
 		fmt.Println("No contract call data found in the session.")
 		return nil
 	}
 
+	annotateTopCalls(stats, simResp)
 	printStatsTable(stats)
 	return nil
 }
 
+// annotateTopCalls decodes the session's call tree and, for each
+// contract in stats, fills in topCall with its most-frequently-invoked
+// function, annotated with that function's cached doc summary if the
+// resolver has one. Docs only ever come from the local cache here
+// (see sourcemap.Resolver.Docs): stats already has everything it needs
+// from the session, so it shouldn't force a registry fetch just to
+// label a column.
+func annotateTopCalls(stats []contractStat, resp *simulator.SimulationResponse) {
+	root, err := decoder.DecodeEvents(resp.RawEvents)
+	if err != nil {
+		logger.Logger.Debug("Failed to decode call tree for doc annotations", "error", err)
+		return
+	}
+
+	counts := make(map[string]map[string]int) // contractID -> function -> calls
+	var walk func(node *decoder.CallNode)
+	walk = func(node *decoder.CallNode) {
+		if node.ContractID != nil && *node.ContractID != "" && node.Function != "" {
+			m, ok := counts[*node.ContractID]
+			if !ok {
+				m = make(map[string]int)
+				counts[*node.ContractID] = m
+			}
+			m[node.Function]++
+		}
+		for _, child := range node.SubCalls {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	resolver := sourcemap.NewResolver(sourcemap.WithCache(resolverCacheDir()))
+	for i := range stats {
+		fns, ok := counts[stats[i].contractID]
+		if !ok {
+			continue
+		}
+		top := topFunction(fns)
+		docs := resolver.Docs(stats[i].contractID)
+		stats[i].topCall = visualizer.AnnotateFunction(top, docs)
+	}
+}
+
+// topFunction returns the most-called key in counts, breaking ties by
+// name for deterministic output.
+func topFunction(counts map[string]int) string {
+	best := ""
+	bestCount := -1
+	for name, count := range counts {
+		if count > bestCount || (count == bestCount && name < best) {
+			best = name
+			bestCount = count
+		}
+	}
+	return best
+}
+
 func loadSimulationResponse(cmd *cobra.Command, id string) (*simulator.SimulationResponse, error) {
 	if id != "" {
 		store, err := session.NewStore()
@@ -84,7 +162,7 @@ func loadSimulationResponse(cmd *cobra.Command, id string) (*simulator.Simulatio
 	return data.ToSimulationResponse()
 }
 
-func buildContractStats(resp *simulator.SimulationResponse) []contractStat {
+func buildContractStats(resp *simulator.SimulationResponse, schedule FeeSchedule, sortBy func(a, b contractStat) bool) []contractStat {
 	index := make(map[string]*contractStat)
 
 	process := func(contractID *string, eventType string) {
@@ -95,20 +173,16 @@ func buildContractStats(resp *simulator.SimulationResponse) []contractStat {
 		if _, ok := index[id]; !ok {
 			index[id] = &contractStat{contractID: id, seenTypes: make(map[string]bool)}
 		}
-		
+
 		s := index[id]
 		s.eventCount++
-		
+
 		lowerType := strings.ToLower(eventType)
 		switch lowerType {
 		case "storage_write":
 			s.storageWrites++
-			s.estimatedCost += uint64(costWeightStorageWrite)
 		case "require_auth", "auth":
 			s.authChecks++
-			s.estimatedCost += uint64(costWeightAuth)
-		default:
-			s.estimatedCost += uint64(costWeightDefault)
 		}
 
 		if !s.seenTypes[lowerType] {
@@ -127,14 +201,24 @@ func buildContractStats(resp *simulator.SimulationResponse) []contractStat {
 		}
 	}
 
+	eventCounts := make(map[string]int, len(index))
+	for id, s := range index {
+		eventCounts[id] = s.eventCount
+	}
+
+	total := resourceUsageFromTransactionData(resp.TransactionData, uint64(len(resp.DiagnosticEvents)), uint64(len(resp.EnvelopeXdr)))
+	perContract := shareAcrossContracts(total, eventCounts)
+
 	result := make([]contractStat, 0, len(index))
-	for _, s := range index {
+	for id, s := range index {
+		s.resources = perContract[id]
+		s.estimatedFeeStroops = estimatedFeeStroops(s.resources, schedule)
 		result = append(result, *s)
 	}
 
 	sort.Slice(result, func(i, j int) bool {
-		if result[i].estimatedCost != result[j].estimatedCost {
-			return result[i].estimatedCost > result[j].estimatedCost
+		if sortBy(result[i], result[j]) != sortBy(result[j], result[i]) {
+			return sortBy(result[i], result[j])
 		}
 		return result[i].contractID < result[j].contractID
 	})
@@ -146,25 +230,29 @@ func buildContractStats(resp *simulator.SimulationResponse) []contractStat {
 }
 
 func printStatsTable(stats []contractStat) {
-	const (
-		colContract = 44
-		colCost     = 12
-		colDepth    = 7
-	)
-
 	fmt.Printf("Top %d most expensive contract calls\n\n", statsTopN)
-	fmt.Printf("%-44s | %-12s | %-7s\n", "Contract ID", "Est. Cost", "Depth")
-	fmt.Println(strings.Repeat("-", colContract+colCost+colDepth+6))
+	fmt.Printf("%-44s | %-10s | %-8s | %-10s | %-10s | %-12s | %-7s | %s\n",
+		"Contract ID", "CPU", "Mem", "RdBytes", "WrBytes", "Fee", "Depth", "Top Call")
+	fmt.Println(strings.Repeat("-", 44+10+8+10+10+12+7+18))
 
 	for i, s := range stats {
 		displayID := s.contractID
-		if len(displayID) > colContract {
-			displayID = displayID[:colContract-3] + "..."
+		if len(displayID) > 44 {
+			displayID = displayID[:41] + "..."
+		}
+		topCall := s.topCall
+		if topCall == "" {
+			topCall = "-"
 		}
-		fmt.Printf("%d. %-41s | %-12d | %-7d\n", i+1, displayID, s.estimatedCost, s.callDepth)
+		fmt.Printf("%d. %-41s | %-10d | %-8d | %-10d | %-10d | %-12d | %-7d | %s\n",
+			i+1, displayID,
+			s.resources.CPUInstructions, s.resources.MemoryBytes,
+			s.resources.ReadBytes, s.resources.WriteBytes,
+			s.estimatedFeeStroops, s.callDepth, topCall)
 	}
 }
 
 func init() {
 	statsCmd.Flags().StringVar(&statsSessionFlag, "session", "", "Load a saved session by ID")
+	statsCmd.Flags().StringVar(&statsSortByFlag, "sort-by", "fee", "Sort by fee, cpu, rdbytes, wrbytes, or depth")
 }
\ No newline at end of file
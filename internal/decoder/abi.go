@@ -0,0 +1,264 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/dotandev/hintents/internal/sourcemap"
+	"github.com/stellar/go/xdr"
+)
+
+// EventABI describes how to decode one contract event, analogous to an
+// entry in a go-ethereum abigen binding. It is sourced from the
+// contract's contractspecv0 entries (the Soroban equivalent of an
+// Ethereum event signature) embedded in the WASM's contractspecv0
+// custom section.
+type EventABI struct {
+	// ContractID restricts this ABI to a single contract. Empty matches
+	// any contract emitting an event with this Name.
+	ContractID string
+	// Name is the event's discriminant, i.e. Topics[1] for a
+	// contract-emitted event (Topics[0] is reserved for "contract").
+	Name string
+	// Topics names each indexed topic after the discriminant, in order.
+	Topics []string
+	// Data names each field carried in the event's Data map/vec, in
+	// declaration order.
+	Data []string
+}
+
+// Registry resolves the EventABI for a given contract and event name.
+// Implementations are expected to load contractspecv0 entries lazily
+// (e.g. from a fetched WASM, see sourcemap.Resolver) and cache them.
+type Registry interface {
+	Lookup(contractID, eventName string) (*EventABI, bool)
+	Register(abi EventABI)
+}
+
+// memoryRegistry is a process-wide, in-memory Registry. It backs
+// DefaultRegistry, which is the registry DecodeEvents always uses.
+type memoryRegistry struct {
+	mu    sync.RWMutex
+	byID  map[string]map[string]EventABI // contractID -> eventName -> abi
+	byAny map[string]EventABI            // eventName -> abi, for ContractID == ""
+}
+
+func newMemoryRegistry() *memoryRegistry {
+	return &memoryRegistry{
+		byID:  make(map[string]map[string]EventABI),
+		byAny: make(map[string]EventABI),
+	}
+}
+
+func (r *memoryRegistry) Register(abi EventABI) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if abi.ContractID == "" {
+		r.byAny[abi.Name] = abi
+		return
+	}
+	m, ok := r.byID[abi.ContractID]
+	if !ok {
+		m = make(map[string]EventABI)
+		r.byID[abi.ContractID] = m
+	}
+	m[abi.Name] = abi
+}
+
+func (r *memoryRegistry) Lookup(contractID, eventName string) (*EventABI, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if m, ok := r.byID[contractID]; ok {
+		if abi, ok := m[eventName]; ok {
+			return &abi, true
+		}
+	}
+	if abi, ok := r.byAny[eventName]; ok {
+		return &abi, true
+	}
+	return nil, false
+}
+
+var defaultRegistry = newMemoryRegistry()
+
+// DefaultRegistry returns the process-wide Registry that DecodeEvents
+// looks every event up in.
+func DefaultRegistry() Registry {
+	return defaultRegistry
+}
+
+// RegisterEventABI adds abi to the default Registry, making it available
+// to every subsequent DecodeEvents call. Callers typically populate this
+// once per contract after resolving its contractspecv0 entries.
+func RegisterEventABI(abi EventABI) {
+	defaultRegistry.Register(abi)
+}
+
+// LoadEventABIsFromWasm parses the contractspecv0 custom section of the
+// WASM at path (via sourcemap.DecodeWasmSpecEntries) and registers an
+// EventABI for every user-defined struct it declares, analogous to how
+// abigen binds a Solidity event to its struct-shaped log data.
+//
+// Soroban's contractspecv0 does not yet carry a dedicated event-topic
+// schema the way an Ethereum ABI does -- it only describes functions and
+// user-defined types. Until the spec grows a first-class event entry,
+// each UDT struct is registered as the likely shape of an event sharing
+// its name, with the struct's own field names as the event's Data
+// fields. Contracts that don't follow this "one struct per event"
+// convention won't get decoded fields from this path; they can still
+// register an EventABI directly with RegisterEventABI.
+func LoadEventABIsFromWasm(contractID, path string) (int, error) {
+	entries, err := sourcemap.DecodeWasmSpecEntries(path)
+	if err != nil {
+		return 0, fmt.Errorf("decode contractspecv0: %w", err)
+	}
+
+	registered := 0
+	for _, entry := range entries {
+		if entry.UdtStructV0 == nil {
+			continue
+		}
+		udt := entry.UdtStructV0
+
+		abi := EventABI{
+			ContractID: contractID,
+			Name:       string(udt.Name),
+		}
+		for _, field := range udt.Fields {
+			abi.Data = append(abi.Data, string(field.Name))
+		}
+
+		RegisterEventABI(abi)
+		registered++
+	}
+	return registered, nil
+}
+
+// reservedDiscriminants are the Topics[0] values DecodeEvents itself
+// uses to frame the call tree. They are never contract event names, so
+// decorateWithABI must not try to match an EventABI against them --
+// Topics[1] for these is the invoked function's name, which can easily
+// collide with an unrelated registered event of the same name.
+var reservedDiscriminants = map[string]bool{
+	"fn_call":   true,
+	"fn_return": true,
+	"log":       true,
+}
+
+// decorateWithABI looks up an EventABI matching evt and, if found,
+// populates evt.Name and evt.Decoded with the named fields it describes.
+// evt.Data and evt.Topics are left untouched so callers retain
+// loss-free access to the original ScVal forms.
+func decorateWithABI(evt *DecodedEvent, reg Registry) {
+	if len(evt.Topics) < 2 {
+		return
+	}
+	if reservedDiscriminants[evt.Topics[0]] {
+		return
+	}
+	contractID := ""
+	if evt.ContractID != nil {
+		contractID = *evt.ContractID
+	}
+	name := evt.Topics[1]
+
+	abi, ok := reg.Lookup(contractID, name)
+	if !ok {
+		return
+	}
+
+	evt.Name = abi.Name
+	evt.ABI = abi
+	evt.Decoded = decodeFields(abi, evt)
+}
+
+func decodeFields(abi *EventABI, evt *DecodedEvent) map[string]any {
+	decoded := make(map[string]any, len(abi.Topics)+len(abi.Data))
+
+	// Indexed topics start at Topics[2] (Topics[0] is "contract",
+	// Topics[1] is the event name).
+	for i, fieldName := range abi.Topics {
+		idx := i + 2
+		if idx >= len(evt.Topics) {
+			break
+		}
+		decoded[fieldName] = evt.Topics[idx]
+	}
+
+	decodeDataFields(abi, evt.Data, decoded)
+	return decoded
+}
+
+func decodeDataFields(abi *EventABI, data xdr.ScVal, decoded map[string]any) {
+	if len(abi.Data) == 0 {
+		return
+	}
+
+	switch data.Type {
+	case xdr.ScValTypeScvMap:
+		if data.Map == nil {
+			return
+		}
+		for _, entry := range *data.Map {
+			key := stringifyScVal(entry.Key)
+			decoded[key] = scValToGo(entry.Val)
+		}
+	case xdr.ScValTypeScvVec:
+		if data.Vec == nil {
+			return
+		}
+		for i, fieldName := range abi.Data {
+			if i >= len(*data.Vec) {
+				break
+			}
+			decoded[fieldName] = scValToGo((*data.Vec)[i])
+		}
+	default:
+		if len(abi.Data) == 1 {
+			decoded[abi.Data[0]] = scValToGo(data)
+		}
+	}
+}
+
+// scValToGo converts an ScVal into a plain Go value suitable for
+// FilterEvents predicates and for display. It intentionally only
+// handles the scalar types event payloads actually use; anything else
+// is returned as its stringified form.
+func scValToGo(v xdr.ScVal) any {
+	switch v.Type {
+	case xdr.ScValTypeScvBool:
+		if v.B != nil {
+			return bool(*v.B)
+		}
+	case xdr.ScValTypeScvU32:
+		if v.U32 != nil {
+			return uint32(*v.U32)
+		}
+	case xdr.ScValTypeScvI32:
+		if v.I32 != nil {
+			return int32(*v.I32)
+		}
+	case xdr.ScValTypeScvU64:
+		if v.U64 != nil {
+			return uint64(*v.U64)
+		}
+	case xdr.ScValTypeScvI64:
+		if v.I64 != nil {
+			return int64(*v.I64)
+		}
+	case xdr.ScValTypeScvU128:
+		if v.U128 != nil {
+			return *v.U128
+		}
+	case xdr.ScValTypeScvI128:
+		if v.I128 != nil {
+			return *v.I128
+		}
+	}
+	return stringifyScVal(v)
+}
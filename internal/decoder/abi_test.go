@@ -0,0 +1,74 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+)
+
+func scValU64(v uint64) xdr.ScVal {
+	u := xdr.Uint64(v)
+	return xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &u}
+}
+
+func TestDecorateWithABISkipsReservedDiscriminants(t *testing.T) {
+	reg := newMemoryRegistry()
+	// A malicious/coincidental registration named after a function the
+	// call tree also frames with fn_call/fn_return.
+	reg.Register(EventABI{Name: "transfer", Data: []string{"amount"}})
+
+	for _, discriminant := range []string{"fn_call", "fn_return", "log"} {
+		evt := DecodedEvent{Topics: []string{discriminant, "transfer"}}
+		decorateWithABI(&evt, reg)
+		assert.Empty(t, evt.Name, "discriminant %q should not be decorated", discriminant)
+		assert.Nil(t, evt.Decoded, "discriminant %q should not be decorated", discriminant)
+	}
+}
+
+func TestDecorateWithABIMatchesRealEvent(t *testing.T) {
+	reg := newMemoryRegistry()
+	reg.Register(EventABI{Name: "transfer", Topics: []string{"from", "to"}, Data: []string{"amount"}})
+
+	evt := DecodedEvent{
+		Topics: []string{"contract", "transfer", "GFROM", "GTO"},
+		Data:   scValU64(1500),
+	}
+	decorateWithABI(&evt, reg)
+
+	assert.Equal(t, "transfer", evt.Name)
+	assert.Equal(t, "GFROM", evt.Decoded["from"])
+	assert.Equal(t, "GTO", evt.Decoded["to"])
+	assert.EqualValues(t, 1500, evt.Decoded["amount"])
+}
+
+func TestFilterEventsMatchesNameContractAndPredicate(t *testing.T) {
+	contractA := "CA"
+	contractB := "CB"
+
+	root := &CallNode{Function: "TOP_LEVEL"}
+	nodeA := &CallNode{Function: "swap", ContractID: &contractA, Events: []DecodedEvent{
+		{Name: "Transfer", ContractID: &contractA, Decoded: map[string]any{"amount": uint64(500)}},
+	}}
+	nodeB := &CallNode{Function: "swap", ContractID: &contractB, Events: []DecodedEvent{
+		{Name: "Transfer", ContractID: &contractB, Decoded: map[string]any{"amount": uint64(5000)}},
+	}}
+	root.SubCalls = []*CallNode{nodeA, nodeB}
+
+	matches := root.FilterEvents(EventQuery{
+		Name: "Transfer",
+		Where: map[string]Predicate{
+			"amount": func(v any) bool {
+				amount, ok := v.(uint64)
+				return ok && amount > 1000
+			},
+		},
+	})
+
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, contractB, *matches[0].Event.ContractID)
+	}
+}
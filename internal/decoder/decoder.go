@@ -0,0 +1,162 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package decoder reconstructs a contract call tree from the diagnostic
+// events emitted during a Soroban transaction and, where an ABI is
+// available for the invoked contract, decodes each event's topics and
+// data into typed, named fields.
+package decoder
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/stellar/go/xdr"
+)
+
+// DecodedEvent is a single contract event attached to the CallNode that
+// emitted it.
+type DecodedEvent struct {
+	// ContractID is the contract that emitted the event, if present on
+	// the underlying xdr.ContractEvent.
+	ContractID *string
+	// Topics holds the stringified form of each topic ScVal, in order.
+	// Topics[0] is conventionally the event's discriminant symbol
+	// (fn_call, fn_return, log, or "contract" for a contract-emitted
+	// event, whose own event name is then Topics[1]).
+	Topics []string
+	// Data is the raw, undecoded event body, kept for loss-free
+	// inspection even when an ABI match is found.
+	Data xdr.ScVal
+
+	// Name is the resolved event name (e.g. "Transfer") when an EventABI
+	// matched this event. Empty otherwise.
+	Name string
+	// ABI is the schema used to decode this event, if one matched.
+	ABI *EventABI
+	// Decoded holds the named, typed fields produced by applying ABI to
+	// Topics and Data. Nil when no ABI matched.
+	Decoded map[string]any
+}
+
+// CallNode is one frame of the reconstructed contract call tree. The
+// root node (returned by DecodeEvents) is a synthetic TOP_LEVEL node
+// whose SubCalls are the outermost contract invocations.
+type CallNode struct {
+	Function   string
+	ContractID *string
+	Events     []DecodedEvent
+	SubCalls   []*CallNode
+}
+
+// DecodeEvents parses a list of base64-encoded XDR DiagnosticEvents (as
+// returned in a transaction's ResultMetaXdr diagnostic events) and
+// reconstructs the call tree implied by their fn_call/fn_return topics.
+//
+// An entry that is not valid base64 or not a valid XDR DiagnosticEvent
+// fails the whole decode with an error. Events without a V0 body or
+// without topics are skipped, and events with any other discriminant
+// are attached to the frame that is open when they arrive.
+func DecodeEvents(events []string) (*CallNode, error) {
+	root := &CallNode{Function: "TOP_LEVEL"}
+	stack := []*CallNode{root}
+	registry := DefaultRegistry()
+
+	for i, raw := range events {
+		var diag xdr.DiagnosticEvent
+		b, err := base64.StdEncoding.DecodeString(raw)
+		if err != nil {
+			return nil, fmt.Errorf("decode event %d: %w", i, err)
+		}
+		if err := xdr.SafeUnmarshal(b, &diag); err != nil {
+			return nil, fmt.Errorf("unmarshal event %d: %w", i, err)
+		}
+
+		body := diag.Event.Body
+		if body.V0 == nil {
+			continue
+		}
+
+		topics := stringifyTopics(body.V0.Topics)
+		if len(topics) == 0 {
+			continue
+		}
+
+		var contractID *string
+		if diag.Event.ContractId != nil {
+			id := diag.Event.ContractId.HexString()
+			contractID = &id
+		}
+
+		evt := DecodedEvent{
+			ContractID: contractID,
+			Topics:     topics,
+			Data:       body.V0.Data,
+		}
+		decorateWithABI(&evt, registry)
+
+		top := stack[len(stack)-1]
+
+		switch topics[0] {
+		case "fn_call":
+			fnName := ""
+			if len(topics) > 1 {
+				fnName = topics[1]
+			}
+			node := &CallNode{Function: fnName, ContractID: contractID}
+			top.SubCalls = append(top.SubCalls, node)
+			node.Events = append(node.Events, evt)
+			stack = append(stack, node)
+		case "fn_return":
+			fnName := ""
+			if len(topics) > 1 {
+				fnName = topics[1]
+			}
+			stack = closeCall(stack, fnName, evt)
+		default:
+			top.Events = append(top.Events, evt)
+		}
+	}
+
+	return root, nil
+}
+
+// closeCall pops the stack until it finds the frame matching fnName,
+// attaching evt to that frame before popping it. Frames above the match
+// never received their fn_return (the call panicked or trapped); they
+// are popped without an event, recording an incomplete-but-honest call.
+func closeCall(stack []*CallNode, fnName string, evt DecodedEvent) []*CallNode {
+	for i := len(stack) - 1; i > 0; i-- {
+		if stack[i].Function == fnName {
+			stack[i].Events = append(stack[i].Events, evt)
+			return stack[:i]
+		}
+	}
+	// No matching frame found (e.g. a return for the top-level call);
+	// attach to whatever is currently on top rather than dropping it.
+	top := stack[len(stack)-1]
+	top.Events = append(top.Events, evt)
+	return stack
+}
+
+func stringifyTopics(topics []xdr.ScVal) []string {
+	out := make([]string, 0, len(topics))
+	for _, t := range topics {
+		out = append(out, stringifyScVal(t))
+	}
+	return out
+}
+
+func stringifyScVal(v xdr.ScVal) string {
+	switch v.Type {
+	case xdr.ScValTypeScvSymbol:
+		if v.Sym != nil {
+			return string(*v.Sym)
+		}
+	case xdr.ScValTypeScvString:
+		if v.Str != nil {
+			return string(*v.Str)
+		}
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Export formats supported by Export.
+const (
+	FormatFolded     = "folded"
+	FormatSpeedscope = "speedscope"
+)
+
+// FrameWeigher returns the cost to attribute to node's own frame (not
+// including its children). Export calls this once per node. The
+// default, used when no WithWeigher option is supplied, weighs each
+// frame by its event count, which is a sample-count proxy rather than a
+// real cost estimate; callers that have resource-model data (see
+// cmd/resources.go) should supply their own weigher so the flamegraph
+// reflects estimated fee instead.
+type FrameWeigher func(node *CallNode) uint64
+
+// ExportOption configures Export.
+type ExportOption func(*exportConfig)
+
+type exportConfig struct {
+	weigher FrameWeigher
+}
+
+// WithWeigher overrides how much cost Export attributes to each frame.
+func WithWeigher(w FrameWeigher) ExportOption {
+	return func(c *exportConfig) { c.weigher = w }
+}
+
+func defaultWeigher(node *CallNode) uint64 {
+	return uint64(len(node.Events))
+}
+
+// Export serializes the call tree rooted at root into format, writing
+// the result to w. Supported formats are "folded" (Brendan Gregg's
+// collapsed-stack text, for flamegraph.pl) and "speedscope" (the
+// speedscope evented profile JSON format).
+func Export(root *CallNode, format string, w io.Writer, opts ...ExportOption) error {
+	cfg := exportConfig{weigher: defaultWeigher}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	switch format {
+	case FormatFolded:
+		return exportFolded(root, cfg.weigher, w)
+	case FormatSpeedscope:
+		return exportSpeedscope(root, cfg.weigher, w)
+	default:
+		return fmt.Errorf("unsupported export format %q (want %q or %q)", format, FormatFolded, FormatSpeedscope)
+	}
+}
+
+func isSyntheticRoot(node *CallNode) bool {
+	return node.Function == "" || node.Function == "TOP_LEVEL"
+}
+
+func frameLabel(node *CallNode) string {
+	if node.ContractID != nil && *node.ContractID != "" {
+		return fmt.Sprintf("%s (%s)", node.Function, *node.ContractID)
+	}
+	return node.Function
+}
+
+// exportFolded writes one "stack;of;frames weight" line per node,
+// matching the format flamegraph.pl and inferno both consume.
+func exportFolded(root *CallNode, weigher FrameWeigher, w io.Writer) error {
+	var walk func(node *CallNode, stack []string) error
+	walk = func(node *CallNode, stack []string) error {
+		frameStack := stack
+		if !isSyntheticRoot(node) {
+			frameStack = append(append([]string{}, stack...), frameLabel(node))
+		}
+
+		if weight := weigher(node); weight > 0 && len(frameStack) > 0 {
+			if _, err := fmt.Fprintf(w, "%s %d\n", strings.Join(frameStack, ";"), weight); err != nil {
+				return err
+			}
+		}
+
+		for _, child := range node.SubCalls {
+			if err := walk(child, frameStack); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(root, nil)
+}
+
+type speedscopeFrame struct {
+	Name string `json:"name"`
+	File string `json:"file,omitempty"`
+}
+
+type speedscopeEvent struct {
+	Type  string `json:"type"`
+	At    uint64 `json:"at"`
+	Frame int    `json:"frame"`
+}
+
+type speedscopeProfile struct {
+	Type       string            `json:"type"`
+	Name       string            `json:"name"`
+	Unit       string            `json:"unit"`
+	StartValue uint64            `json:"startValue"`
+	EndValue   uint64            `json:"endValue"`
+	Events     []speedscopeEvent `json:"events"`
+}
+
+type speedscopeDocument struct {
+	Schema string `json:"$schema"`
+	Shared struct {
+		Frames []speedscopeFrame `json:"frames"`
+	} `json:"shared"`
+	Profiles []speedscopeProfile `json:"profiles"`
+}
+
+// exportSpeedscope writes the speedscope evented profile format,
+// mapping each fn_call/fn_return pair to an openFrame/closeFrame event
+// and each contract ID to a frame's "file", which speedscope uses to
+// color-group frames.
+func exportSpeedscope(root *CallNode, weigher FrameWeigher, w io.Writer) error {
+	var doc speedscopeDocument
+	doc.Schema = "https://www.speedscope.app/file-format-schema.json"
+
+	frameIndex := make(map[string]int)
+	var events []speedscopeEvent
+	var at uint64
+
+	var walk func(node *CallNode)
+	walk = func(node *CallNode) {
+		isRoot := isSyntheticRoot(node)
+		var frameIdx int
+		if !isRoot {
+			key := frameLabel(node)
+			idx, ok := frameIndex[key]
+			if !ok {
+				idx = len(doc.Shared.Frames)
+				frameIndex[key] = idx
+				file := ""
+				if node.ContractID != nil {
+					file = *node.ContractID
+				}
+				doc.Shared.Frames = append(doc.Shared.Frames, speedscopeFrame{Name: node.Function, File: file})
+			}
+			frameIdx = idx
+			events = append(events, speedscopeEvent{Type: "O", At: at, Frame: frameIdx})
+		}
+
+		at += weigher(node)
+
+		for _, child := range node.SubCalls {
+			walk(child)
+		}
+
+		if !isRoot {
+			events = append(events, speedscopeEvent{Type: "C", At: at, Frame: frameIdx})
+		}
+	}
+	walk(root)
+
+	doc.Profiles = []speedscopeProfile{{
+		Type:       "evented",
+		Name:       "erst call tree",
+		Unit:       "none",
+		StartValue: 0,
+		EndValue:   at,
+		Events:     events,
+	}}
+
+	enc := json.NewEncoder(w)
+	return enc.Encode(&doc)
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleTree() *CallNode {
+	contractA := "CA"
+	contractB := "CB"
+	return &CallNode{
+		Function: "TOP_LEVEL",
+		SubCalls: []*CallNode{
+			{
+				Function:   "swap",
+				ContractID: &contractA,
+				Events:     []DecodedEvent{{Name: "Transfer"}, {Name: "Transfer"}},
+				SubCalls: []*CallNode{
+					{
+						Function:   "transfer",
+						ContractID: &contractB,
+						Events:     []DecodedEvent{{Name: "Transfer"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestExportFoldedUsesDefaultEventCountWeigher(t *testing.T) {
+	var buf strings.Builder
+	err := Export(sampleTree(), FormatFolded, &buf)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	assert.Contains(t, lines, "swap (CA) 2")
+	assert.Contains(t, lines, "swap (CA);transfer (CB) 1")
+}
+
+func TestExportFoldedHonorsCustomWeigher(t *testing.T) {
+	var buf strings.Builder
+	weigher := func(node *CallNode) uint64 { return 100 }
+	err := Export(sampleTree(), FormatFolded, &buf, WithWeigher(weigher))
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	assert.Contains(t, lines, "swap (CA) 100")
+	assert.Contains(t, lines, "swap (CA);transfer (CB) 100")
+}
+
+func TestExportSpeedscopeProducesOpenCloseEvents(t *testing.T) {
+	var buf strings.Builder
+	err := Export(sampleTree(), FormatSpeedscope, &buf)
+	require.NoError(t, err)
+
+	var doc speedscopeDocument
+	require.NoError(t, json.Unmarshal([]byte(buf.String()), &doc))
+
+	require.Len(t, doc.Shared.Frames, 2)
+	assert.Equal(t, "swap", doc.Shared.Frames[0].Name)
+	assert.Equal(t, "CA", doc.Shared.Frames[0].File)
+	assert.Equal(t, "transfer", doc.Shared.Frames[1].Name)
+	assert.Equal(t, "CB", doc.Shared.Frames[1].File)
+
+	require.Len(t, doc.Profiles, 1)
+	events := doc.Profiles[0].Events
+	require.Len(t, events, 4)
+	assert.Equal(t, "O", events[0].Type)
+	assert.Equal(t, "O", events[1].Type)
+	assert.Equal(t, "C", events[2].Type)
+	assert.Equal(t, "C", events[3].Type)
+	assert.Equal(t, doc.Profiles[0].EndValue, events[3].At)
+}
+
+func TestExportRejectsUnknownFormat(t *testing.T) {
+	var buf strings.Builder
+	err := Export(sampleTree(), "bogus", &buf)
+	assert.Error(t, err)
+}
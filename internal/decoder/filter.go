@@ -0,0 +1,74 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+// Predicate tests a single decoded field value from a matched event.
+// Returning false excludes the event from the FilterEvents result.
+type Predicate func(value any) bool
+
+// EventQuery describes a search over a decoded call tree. All non-zero
+// fields are ANDed together.
+type EventQuery struct {
+	// Name matches DecodedEvent.Name exactly (e.g. "Transfer"). Empty
+	// matches any event name.
+	Name string
+	// ContractID restricts the search to events emitted by this
+	// contract. Empty matches any contract.
+	ContractID string
+	// Where applies per-field predicates against DecodedEvent.Decoded.
+	// A field present in Where but absent from an event's Decoded map
+	// fails the match.
+	Where map[string]Predicate
+}
+
+// MatchedEvent pairs a DecodedEvent with the CallNode that emitted it,
+// since FilterEvents flattens results across the whole tree.
+type MatchedEvent struct {
+	Node  *CallNode
+	Event DecodedEvent
+}
+
+// FilterEvents walks root and every descendant, returning every event
+// that satisfies query. Events without a decoded ABI never match a
+// query with Name or Where set, since there would be nothing to compare
+// against; they can still match a bare ContractID-only query.
+func (root *CallNode) FilterEvents(query EventQuery) []MatchedEvent {
+	var out []MatchedEvent
+	walkCallTree(root, func(node *CallNode) {
+		for _, evt := range node.Events {
+			if eventMatches(evt, query) {
+				out = append(out, MatchedEvent{Node: node, Event: evt})
+			}
+		}
+	})
+	return out
+}
+
+func walkCallTree(node *CallNode, visit func(*CallNode)) {
+	visit(node)
+	for _, child := range node.SubCalls {
+		walkCallTree(child, visit)
+	}
+}
+
+func eventMatches(evt DecodedEvent, query EventQuery) bool {
+	if query.ContractID != "" {
+		if evt.ContractID == nil || *evt.ContractID != query.ContractID {
+			return false
+		}
+	}
+
+	if query.Name != "" && evt.Name != query.Name {
+		return false
+	}
+
+	for field, pred := range query.Where {
+		value, ok := evt.Decoded[field]
+		if !ok || !pred(value) {
+			return false
+		}
+	}
+
+	return true
+}
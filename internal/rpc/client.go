@@ -8,15 +8,43 @@ import (
 	"github.com/stellar/go/clients/horizonclient"
 )
 
-// Client handles interactions with the Stellar Network
+// Client handles interactions with the Stellar Network. Horizon serves
+// historical/post-mortem lookups; Soroban is used for anything that
+// needs a live simulation (Soroban-RPC does not archive history the way
+// Horizon does).
 type Client struct {
 	Horizon *horizonclient.Client
+	Soroban *SorobanRPC
 }
 
 // NewClient creates a new RPC client (defaults to Public Network for now)
 func NewClient() *Client {
+	return NewClientForNetwork(NetworkPublic)
+}
+
+// NewClientForNetwork creates a Client wired to both the Horizon and
+// Soroban-RPC endpoints for network, e.g. so `--network testnet` can
+// retarget every downstream lookup with one flag.
+func NewClientForNetwork(network Network) *Client {
+	horizon := horizonclient.DefaultPublicNetClient
+	if network.Name != NetworkPublic.Name {
+		horizon = &horizonclient.Client{HorizonURL: defaultHorizonURL(network)}
+	}
+
 	return &Client{
-		Horizon: horizonclient.DefaultPublicNetClient,
+		Horizon: horizon,
+		Soroban: NewSorobanRPC(network.RPCURL),
+	}
+}
+
+func defaultHorizonURL(network Network) string {
+	switch network.Name {
+	case NetworkTestnet.Name:
+		return "https://horizon-testnet.stellar.org"
+	case NetworkFuturenet.Name:
+		return "https://horizon-futurenet.stellar.org"
+	default:
+		return horizonclient.DefaultPublicNetClient.HorizonURL
 	}
 }
 
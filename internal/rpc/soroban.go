@@ -0,0 +1,374 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/stellar/go/xdr"
+)
+
+// Network bundles the coordinates needed to talk to a given Stellar
+// network over Soroban-RPC.
+type Network struct {
+	Name       string
+	RPCURL     string
+	Passphrase string
+}
+
+var (
+	NetworkPublic = Network{
+		Name:       "public",
+		RPCURL:     "https://soroban-rpc.mainnet.stellar.gateway.fm",
+		Passphrase: "Public Global Stellar Network ; September 2015",
+	}
+	NetworkTestnet = Network{
+		Name:       "testnet",
+		RPCURL:     "https://soroban-testnet.stellar.org",
+		Passphrase: "Test SDF Network ; September 2015",
+	}
+	NetworkFuturenet = Network{
+		Name:       "futurenet",
+		RPCURL:     "https://rpc-futurenet.stellar.org",
+		Passphrase: "Test SDF Future Network ; October 2022",
+	}
+)
+
+// SorobanRPC is a minimal JSON-RPC client for a Soroban-RPC node. Unlike
+// the Horizon client it can drive simulateTransaction, so it is what the
+// simulator package reaches for when it needs fresh footprint/auth/
+// budget data instead of replaying a landed transaction's ResultMetaXdr.
+type SorobanRPC struct {
+	endpoint   string
+	httpClient *http.Client
+	maxRetries int
+	retryWait  time.Duration
+}
+
+// SorobanOption configures a SorobanRPC client.
+type SorobanOption func(*SorobanRPC)
+
+// WithDialer overrides the net.Dialer used for outbound connections,
+// e.g. to set a custom KeepAlive or bind to a specific interface.
+func WithDialer(dialer *net.Dialer) SorobanOption {
+	return func(c *SorobanRPC) {
+		transport := &http.Transport{DialContext: dialer.DialContext}
+		c.httpClient.Transport = transport
+	}
+}
+
+// WithTimeout overrides the per-request timeout. Defaults to 30s.
+func WithTimeout(timeout time.Duration) SorobanOption {
+	return func(c *SorobanRPC) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithRetry overrides the retry/backoff policy. Defaults to 3 retries
+// with a 250ms base wait, doubling on each attempt.
+func WithRetry(maxRetries int, baseWait time.Duration) SorobanOption {
+	return func(c *SorobanRPC) {
+		c.maxRetries = maxRetries
+		c.retryWait = baseWait
+	}
+}
+
+// NewSorobanRPC creates a client for the given Soroban-RPC endpoint.
+func NewSorobanRPC(endpoint string, opts ...SorobanOption) *SorobanRPC {
+	c := &SorobanRPC{
+		endpoint: endpoint,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		maxRetries: 3,
+		retryWait:  250 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type jsonRPCRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type jsonRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int             `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *jsonRPCError   `json:"error,omitempty"`
+}
+
+// call invokes method with params and decodes the result into out,
+// retrying transport-level failures with exponential backoff. JSON-RPC
+// errors (the server answered, but with an "error" field) are not
+// retried, since retrying a malformed request just repeats the failure.
+func (c *SorobanRPC) call(ctx context.Context, method string, params, out any) error {
+	body, err := json.Marshal(jsonRPCRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	wait := c.retryWait
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			logger.Logger.Debug("retrying soroban-rpc call", "method", method, "attempt", attempt)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+			wait *= 2
+		}
+
+		resp, err := c.doRequest(ctx, body)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if resp.Error != nil {
+			return fmt.Errorf("soroban-rpc %s: %s (code %d)", method, resp.Error.Message, resp.Error.Code)
+		}
+		if out != nil {
+			if err := json.Unmarshal(resp.Result, out); err != nil {
+				return fmt.Errorf("unmarshal %s result: %w", method, err)
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("soroban-rpc %s failed after %d attempts: %w", method, c.maxRetries+1, lastErr)
+}
+
+func (c *SorobanRPC) doRequest(ctx context.Context, body []byte) (*jsonRPCResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	data, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	var resp jsonRPCResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal envelope: %w", err)
+	}
+	return &resp, nil
+}
+
+// SimulateTransactionResult is the decoded result of a simulateTransaction
+// call. Cost and auth/footprint fields mirror the Soroban-RPC response
+// shape closely enough for the simulator package to build a
+// SimulationResponse from it without a post-mortem ResultMetaXdr.
+type SimulateTransactionResult struct {
+	LatestLedger    int64    `json:"latestLedger"`
+	TransactionData string   `json:"transactionData"`
+	MinResourceFee  string   `json:"minResourceFee"`
+	Error           string   `json:"error,omitempty"`
+	Events          []string `json:"events,omitempty"`
+	Results         []struct {
+		XDR string `json:"xdr"`
+	} `json:"results,omitempty"`
+}
+
+// SimulateTransaction runs simulateTransaction for a base64 transaction
+// envelope, giving callers footprint/auth/budget estimates before the
+// transaction is ever submitted.
+func (c *SorobanRPC) SimulateTransaction(ctx context.Context, envelopeXDR string) (*SimulateTransactionResult, error) {
+	var result SimulateTransactionResult
+	params := map[string]string{"transaction": envelopeXDR}
+	if err := c.call(ctx, "simulateTransaction", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// EventFilter narrows a getEvents call to a ledger range, event type,
+// and set of contract IDs.
+type EventFilter struct {
+	StartLedger int64    `json:"startLedger,omitempty"`
+	EventType   string   `json:"type,omitempty"`
+	ContractIDs []string `json:"contractIds,omitempty"`
+	Cursor      string   `json:"-"`
+	Limit       int      `json:"-"`
+}
+
+// EventInfo is one event returned by getEvents.
+type EventInfo struct {
+	ContractID     string   `json:"contractId"`
+	ID             string   `json:"id"`
+	PagingToken    string   `json:"pagingToken"`
+	Topic          []string `json:"topic"`
+	Value          string   `json:"value"`
+	LedgerClosedAt string   `json:"ledgerClosedAt"`
+}
+
+// GetEventsResult is the decoded result of a getEvents call, along with
+// the cursor to pass back in for the next page.
+type GetEventsResult struct {
+	Events       []EventInfo `json:"events"`
+	LatestLedger int64       `json:"latestLedger"`
+	Cursor       string      `json:"cursor"`
+}
+
+// GetEvents fetches one page of events matching filter. Callers wanting
+// every matching event should loop, feeding the returned Cursor back
+// into filter.Cursor until Events comes back empty.
+func (c *SorobanRPC) GetEvents(ctx context.Context, filter EventFilter) (*GetEventsResult, error) {
+	pagination := map[string]any{}
+	if filter.Cursor != "" {
+		pagination["cursor"] = filter.Cursor
+	}
+	if filter.Limit > 0 {
+		pagination["limit"] = filter.Limit
+	}
+
+	params := map[string]any{
+		"startLedger": filter.StartLedger,
+		"pagination":  pagination,
+	}
+	if len(filter.ContractIDs) > 0 || filter.EventType != "" {
+		eventFilter := map[string]any{}
+		if filter.EventType != "" {
+			eventFilter["type"] = filter.EventType
+		}
+		if len(filter.ContractIDs) > 0 {
+			eventFilter["contractIds"] = filter.ContractIDs
+		}
+		params["filters"] = []any{eventFilter}
+	}
+
+	var result GetEventsResult
+	if err := c.call(ctx, "getEvents", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// LedgerEntryResult is one entry returned by getLedgerEntries.
+type LedgerEntryResult struct {
+	Key                string `json:"key"`
+	XDR                string `json:"xdr"`
+	LastModifiedLedger int64  `json:"lastModifiedLedgerSeq"`
+	LiveUntilLedger    *int64 `json:"liveUntilLedgerSeq,omitempty"`
+}
+
+// GetLedgerEntriesResult is the decoded result of a getLedgerEntries call.
+type GetLedgerEntriesResult struct {
+	Entries      []LedgerEntryResult `json:"entries"`
+	LatestLedger int64               `json:"latestLedger"`
+}
+
+// GetLedgerEntries fetches the current value of each base64 LedgerKey
+// XDR in keys, e.g. to resolve the storage footprint a simulation
+// reported.
+func (c *SorobanRPC) GetLedgerEntries(ctx context.Context, keys []string) (*GetLedgerEntriesResult, error) {
+	var result GetLedgerEntriesResult
+	params := map[string][]string{"keys": keys}
+	if err := c.call(ctx, "getLedgerEntries", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetTransactionResult is the decoded result of a getTransaction call.
+type GetTransactionResult struct {
+	Status        string `json:"status"`
+	LatestLedger  int64  `json:"latestLedger"`
+	EnvelopeXdr   string `json:"envelopeXdr,omitempty"`
+	ResultXdr     string `json:"resultXdr,omitempty"`
+	ResultMetaXdr string `json:"resultMetaXdr,omitempty"`
+}
+
+// Named contractComputeV0 / contractLedgerCostV0 / contractEventsV0 /
+// contractBandwidthV0 config settings, as stored in the network's
+// CONFIG_SETTING ledger entries. GetNetworkConfig accepts one of these.
+const (
+	ConfigSettingContractComputeV0    = "contractComputeV0"
+	ConfigSettingContractLedgerCostV0 = "contractLedgerCostV0"
+	ConfigSettingContractEventsV0     = "contractEventsV0"
+	ConfigSettingContractBandwidthV0  = "contractBandwidthV0"
+)
+
+var configSettingIDs = map[string]xdr.ConfigSettingId{
+	ConfigSettingContractComputeV0:    xdr.ConfigSettingIdConfigSettingContractComputeV0,
+	ConfigSettingContractLedgerCostV0: xdr.ConfigSettingIdConfigSettingContractLedgerCostV0,
+	ConfigSettingContractEventsV0:     xdr.ConfigSettingIdConfigSettingContractEventsV0,
+	ConfigSettingContractBandwidthV0:  xdr.ConfigSettingIdConfigSettingContractBandwidthV0,
+}
+
+// GetNetworkConfig fetches a single named network config setting (CPU/
+// memory/ledger-IO/bandwidth fee parameters) as a ledger entry. Callers
+// decode LedgerEntryResult.XDR into an xdr.LedgerEntryData themselves,
+// since each setting has a different payload shape.
+func (c *SorobanRPC) GetNetworkConfig(ctx context.Context, setting string) (*LedgerEntryResult, error) {
+	id, ok := configSettingIDs[setting]
+	if !ok {
+		return nil, fmt.Errorf("unknown config setting %q", setting)
+	}
+
+	key := xdr.LedgerKey{
+		Type: xdr.LedgerEntryTypeConfigSetting,
+		ConfigSetting: &xdr.LedgerKeyConfigSetting{
+			ConfigSettingId: id,
+		},
+	}
+	keyBytes, err := key.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("marshal config setting key: %w", err)
+	}
+	keyXDR := base64.StdEncoding.EncodeToString(keyBytes)
+
+	result, err := c.GetLedgerEntries(ctx, []string{keyXDR})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Entries) == 0 {
+		return nil, fmt.Errorf("config setting %q not found", setting)
+	}
+	return &result.Entries[0], nil
+}
+
+// GetTransaction fetches a transaction's status and XDR by hash directly
+// from the RPC node, for networks or tooling that does not have a
+// Horizon instance available.
+func (c *SorobanRPC) GetTransaction(ctx context.Context, hash string) (*GetTransactionResult, error) {
+	var result GetTransactionResult
+	params := map[string]string{"hash": hash}
+	if err := c.call(ctx, "getTransaction", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,116 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSorobanRPCCallRetriesThenSucceeds(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			// Simulate a transport failure by closing the connection
+			// without a response.
+			hj, ok := w.(http.Hijacker)
+			require.True(t, ok)
+			conn, _, err := hj.Hijack()
+			require.NoError(t, err)
+			conn.Close()
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(jsonRPCResponse{
+			JSONRPC: "2.0",
+			ID:      1,
+			Result:  json.RawMessage(`{"latestLedger": 42}`),
+		})
+	}))
+	defer server.Close()
+
+	client := NewSorobanRPC(server.URL, WithRetry(3, time.Millisecond))
+
+	var result struct {
+		LatestLedger int64 `json:"latestLedger"`
+	}
+	err := client.call(context.Background(), "getLatestLedger", nil, &result)
+	require.NoError(t, err)
+	assert.EqualValues(t, 42, result.LatestLedger)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&attempts))
+}
+
+func TestSorobanRPCCallReturnsJSONRPCErrorWithoutRetrying(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(jsonRPCResponse{
+			JSONRPC: "2.0",
+			ID:      1,
+			Error:   &jsonRPCError{Code: -32602, Message: "invalid params"},
+		})
+	}))
+	defer server.Close()
+
+	client := NewSorobanRPC(server.URL, WithRetry(3, time.Millisecond))
+
+	err := client.call(context.Background(), "simulateTransaction", nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid params")
+	assert.EqualValues(t, 1, atomic.LoadInt32(&attempts))
+}
+
+func TestSorobanRPCCallFailsAfterExhaustingRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hj, ok := w.(http.Hijacker)
+		require.True(t, ok)
+		conn, _, err := hj.Hijack()
+		require.NoError(t, err)
+		conn.Close()
+	}))
+	defer server.Close()
+
+	client := NewSorobanRPC(server.URL, WithRetry(2, time.Millisecond))
+
+	err := client.call(context.Background(), "getTransaction", nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed after 3 attempts")
+}
+
+func TestGetEventsSendsPaginationAndFilters(t *testing.T) {
+	var captured jsonRPCRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&captured))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(jsonRPCResponse{
+			JSONRPC: "2.0",
+			ID:      1,
+			Result:  json.RawMessage(`{"events": [], "latestLedger": 7, "cursor": "next"}`),
+		})
+	}))
+	defer server.Close()
+
+	client := NewSorobanRPC(server.URL)
+	result, err := client.GetEvents(context.Background(), EventFilter{
+		StartLedger: 100,
+		ContractIDs: []string{"CONTRACT"},
+		Cursor:      "prev",
+		Limit:       10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "next", result.Cursor)
+	assert.Equal(t, "getEvents", captured.Method)
+
+	params, ok := captured.Params.(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{"cursor": "prev", "limit": float64(10)}, params["pagination"])
+	assert.Equal(t, []any{map[string]any{"contractIds": []any{"CONTRACT"}}}, params["filters"])
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package sourcemap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/stellar/go/xdr"
+)
+
+// FunctionDoc is the natspec-style documentation for a single contract
+// function, analogous to solc's userdoc/devdoc pair.
+type FunctionDoc struct {
+	// UserDoc is the short, human-facing summary (Soroban's per-function
+	// Doc string in its contractspecv0 entry).
+	UserDoc string
+	// DevDoc holds the longer, implementation-facing notes: parameter
+	// descriptions, invariants, and error codes. Soroban does not yet
+	// split this from UserDoc the way solc's @notice/@dev tags do, so
+	// today DevDoc mirrors UserDoc; it is its own field so a richer
+	// source (verified Rust doc comments) can populate it independently.
+	DevDoc string
+}
+
+// ContractDoc is the per-function documentation extracted for one
+// contract, keyed by function name.
+type ContractDoc struct {
+	Functions map[string]FunctionDoc
+}
+
+const wasmCustomSectionID = 0
+const contractSpecSectionName = "contractspecv0"
+
+// LoadContractDocFromWasm reads the WASM at path and extracts a
+// ContractDoc from its contractspecv0 custom section. Resolver.Resolve
+// calls it on the path PromptForWasmPath returns; a WASM fetched from
+// the registry is parsed in memory by extractDocs instead.
+func LoadContractDocFromWasm(path string) (*ContractDoc, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read wasm: %w", err)
+	}
+	return parseContractDoc(data)
+}
+
+func parseContractDoc(wasm []byte) (*ContractDoc, error) {
+	section, err := findWasmCustomSection(wasm, contractSpecSectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := decodeSpecEntries(section)
+	if err != nil {
+		return nil, fmt.Errorf("decode contractspecv0: %w", err)
+	}
+
+	doc := &ContractDoc{Functions: make(map[string]FunctionDoc)}
+	for _, entry := range entries {
+		if entry.FunctionV0 == nil {
+			continue
+		}
+		fn := entry.FunctionV0
+		name := string(fn.Name)
+		summary := strings.TrimSpace(string(fn.Doc))
+		doc.Functions[name] = FunctionDoc{
+			UserDoc: summary,
+			DevDoc:  summary,
+		}
+	}
+	return doc, nil
+}
+
+// DecodeWasmSpecEntries reads the WASM at path and decodes its
+// contractspecv0 custom section into its constituent ScSpecEntry
+// records. It is the lower-level counterpart to LoadContractDocFromWasm
+// for callers that need more than per-function docs out of the spec,
+// e.g. decoder's EventABI registry deriving event schemas from a
+// contract's user-defined types.
+func DecodeWasmSpecEntries(path string) ([]xdr.ScSpecEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read wasm: %w", err)
+	}
+	section, err := findWasmCustomSection(data, contractSpecSectionName)
+	if err != nil {
+		return nil, err
+	}
+	return decodeSpecEntries(section)
+}
+
+// decodeSpecEntries reads consecutive XDR-encoded ScSpecEntry records
+// from a contractspecv0 section until the buffer is exhausted.
+func decodeSpecEntries(section []byte) ([]xdr.ScSpecEntry, error) {
+	var entries []xdr.ScSpecEntry
+	r := bytes.NewReader(section)
+	for r.Len() > 0 {
+		var entry xdr.ScSpecEntry
+		if _, err := xdr.Unmarshal(r, &entry); err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// findWasmCustomSection scans a WASM binary's section headers for a
+// custom section named name and returns its payload, stripped of the
+// name's own length-prefixed encoding.
+func findWasmCustomSection(wasm []byte, name string) ([]byte, error) {
+	const wasmHeaderLen = 8 // 4-byte magic + 4-byte version
+	if len(wasm) < wasmHeaderLen {
+		return nil, fmt.Errorf("not a wasm module")
+	}
+
+	pos := wasmHeaderLen
+	for pos < len(wasm) {
+		id := wasm[pos]
+		pos++
+
+		size, n := binary.Uvarint(wasm[pos:])
+		if n <= 0 {
+			return nil, fmt.Errorf("malformed section length at byte %d", pos)
+		}
+		pos += n
+
+		end := pos + int(size)
+		if end > len(wasm) {
+			return nil, fmt.Errorf("section at byte %d overruns module", pos)
+		}
+
+		if id == wasmCustomSectionID {
+			body := wasm[pos:end]
+			nameLen, nn := binary.Uvarint(body)
+			if nn > 0 && int(nameLen) <= len(body)-nn {
+				sectionName := string(body[nn : nn+int(nameLen)])
+				if sectionName == name {
+					return body[nn+int(nameLen):], nil
+				}
+			}
+		}
+
+		pos = end
+	}
+
+	return nil, fmt.Errorf("no %q custom section found", name)
+}
+
+// loadDocsFromSource is the doc-comment fallback extractDocs uses when
+// the registry returned no WASM, or one that yields no function docs,
+// for a contract whose verified Rust source was fetched. It reads only
+// the source files, not Cargo.toml, and picks up `///` doc comments that
+// immediately precede a `pub fn`, which is the natspec-equivalent
+// convention Soroban contracts already follow.
+func loadDocsFromSource(files map[string]string) *ContractDoc {
+	doc := &ContractDoc{Functions: make(map[string]FunctionDoc)}
+
+	for _, content := range files {
+		var pendingDoc []string
+		for _, line := range strings.Split(content, "\n") {
+			trimmed := strings.TrimSpace(line)
+			switch {
+			case strings.HasPrefix(trimmed, "///"):
+				pendingDoc = append(pendingDoc, strings.TrimSpace(strings.TrimPrefix(trimmed, "///")))
+			case strings.HasPrefix(trimmed, "pub fn "):
+				name := functionNameFromSignature(trimmed)
+				if name != "" && len(pendingDoc) > 0 {
+					summary := strings.TrimSpace(strings.Join(pendingDoc, " "))
+					doc.Functions[name] = FunctionDoc{UserDoc: summary, DevDoc: summary}
+				}
+				pendingDoc = nil
+			case trimmed == "":
+				// blank lines don't break a doc block
+			default:
+				pendingDoc = nil
+			}
+		}
+	}
+
+	return doc
+}
+
+func functionNameFromSignature(sig string) string {
+	sig = strings.TrimPrefix(sig, "pub fn ")
+	if idx := strings.IndexAny(sig, "(<"); idx >= 0 {
+		return strings.TrimSpace(sig[:idx])
+	}
+	return ""
+}
+
@@ -0,0 +1,152 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package sourcemap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildWasm assembles a minimal WASM module (just the 8-byte header) with
+// a single custom section named sectionName, whose payload is the
+// concatenation of each entry's XDR encoding -- matching the layout
+// findWasmCustomSection expects.
+func buildWasm(t *testing.T, sectionName string, entries ...xdr.ScSpecEntry) []byte {
+	var payload bytes.Buffer
+	for _, entry := range entries {
+		b, err := entry.MarshalBinary()
+		require.NoError(t, err)
+		payload.Write(b)
+	}
+
+	var body bytes.Buffer
+	nameLen := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(nameLen, uint64(len(sectionName)))
+	body.Write(nameLen[:n])
+	body.WriteString(sectionName)
+	body.Write(payload.Bytes())
+
+	var wasm bytes.Buffer
+	wasm.WriteString("\x00asm")
+	wasm.Write([]byte{1, 0, 0, 0}) // version 1
+
+	wasm.WriteByte(wasmCustomSectionID)
+	sizeBuf := make([]byte, binary.MaxVarintLen64)
+	sn := binary.PutUvarint(sizeBuf, uint64(body.Len()))
+	wasm.Write(sizeBuf[:sn])
+	wasm.Write(body.Bytes())
+
+	return wasm.Bytes()
+}
+
+func TestParseContractDocExtractsFunctionDocs(t *testing.T) {
+	wasm := buildWasm(t, contractSpecSectionName,
+		xdr.ScSpecEntry{
+			Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0,
+			FunctionV0: &xdr.ScSpecFunctionV0{
+				Name: xdr.ScSymbol("transfer"),
+				Doc:  "Moves amount from one account to another.",
+			},
+		},
+	)
+
+	doc, err := parseContractDoc(wasm)
+	require.NoError(t, err)
+	require.Contains(t, doc.Functions, "transfer")
+	assert.Equal(t, "Moves amount from one account to another.", doc.Functions["transfer"].UserDoc)
+	assert.Equal(t, "Moves amount from one account to another.", doc.Functions["transfer"].DevDoc)
+}
+
+func TestLoadContractDocFromWasmReadsFile(t *testing.T) {
+	wasm := buildWasm(t, contractSpecSectionName,
+		xdr.ScSpecEntry{
+			Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0,
+			FunctionV0: &xdr.ScSpecFunctionV0{
+				Name: xdr.ScSymbol("mint"),
+				Doc:  "Mints new tokens to an address.",
+			},
+		},
+	)
+
+	path := filepath.Join(t.TempDir(), "contract.wasm")
+	require.NoError(t, os.WriteFile(path, wasm, 0o644))
+
+	doc, err := LoadContractDocFromWasm(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Mints new tokens to an address.", doc.Functions["mint"].UserDoc)
+}
+
+func TestDecodeWasmSpecEntriesReturnsUdtStructs(t *testing.T) {
+	wasm := buildWasm(t, contractSpecSectionName,
+		xdr.ScSpecEntry{
+			Kind: xdr.ScSpecEntryKindScSpecEntryUdtStructV0,
+			UdtStructV0: &xdr.ScSpecUdtStructV0{
+				Name: xdr.ScSymbol("Transfer"),
+				Fields: []xdr.ScSpecUdtStructFieldV0{
+					{Name: xdr.ScSymbol("from")},
+					{Name: xdr.ScSymbol("to")},
+					{Name: xdr.ScSymbol("amount")},
+				},
+			},
+		},
+	)
+
+	path := filepath.Join(t.TempDir(), "contract.wasm")
+	require.NoError(t, os.WriteFile(path, wasm, 0o644))
+
+	entries, err := DecodeWasmSpecEntries(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.NotNil(t, entries[0].UdtStructV0)
+	assert.Equal(t, "Transfer", string(entries[0].UdtStructV0.Name))
+	assert.Len(t, entries[0].UdtStructV0.Fields, 3)
+}
+
+func TestFindWasmCustomSectionMissingSection(t *testing.T) {
+	wasm := buildWasm(t, "some-other-section")
+	_, err := parseContractDoc(wasm)
+	assert.Error(t, err)
+}
+
+func TestFindWasmCustomSectionRejectsNonWasm(t *testing.T) {
+	_, err := findWasmCustomSection([]byte("not a wasm file"), contractSpecSectionName)
+	assert.Error(t, err)
+}
+
+func TestLoadDocsFromSourcePicksUpDocCommentsBeforePubFn(t *testing.T) {
+	files := map[string]string{
+		"lib.rs": `
+/// Moves amount from one account to another.
+/// Requires auth from the sender.
+pub fn transfer(env: Env, from: Address, to: Address, amount: i128) {
+}
+
+pub fn undocumented(env: Env) {
+}
+`,
+	}
+
+	doc := loadDocsFromSource(files)
+	require.Contains(t, doc.Functions, "transfer")
+	assert.Equal(t, "Moves amount from one account to another. Requires auth from the sender.", doc.Functions["transfer"].UserDoc)
+	assert.NotContains(t, doc.Functions, "undocumented")
+}
+
+func TestFunctionNameFromSignature(t *testing.T) {
+	cases := map[string]string{
+		"pub fn transfer(env: Env, to: Address)": "transfer",
+		"pub fn balance<T>(env: Env)":             "balance",
+		"pub fn ()":                               "",
+	}
+	for sig, want := range cases {
+		assert.Equal(t, want, functionNameFromSignature(sig))
+	}
+}
@@ -81,22 +81,35 @@ func (r *Resolver) Resolve(ctx context.Context, contractID string) (*SourceCode,
 	// 3. Fallback: Prompt user if source is unresolved (Issue #372)
 	if source == nil {
 		logger.Logger.Info("Contract source unresolved automatically", "contract_id", contractID)
-		
+
 		manualPath, err := r.PromptForWasmPath()
 		if err != nil {
 			return nil, fmt.Errorf("failed to get manual WASM path: %w", err)
 		}
 
-		if manualPath != "" {
-			// In a real scenario, you might attempt to load symbols from this path 
-			// using the dwarf.Parser here. For now, we log the path as per requirements.
-			logger.Logger.Info("Manual WASM path provided by user", "path", manualPath)
+		if manualPath == "" {
+			return nil, nil
 		}
-		
-		return nil, nil
+
+		doc, err := LoadContractDocFromWasm(manualPath)
+		if err != nil {
+			logger.Logger.Warn("Failed to extract contract docs from manual WASM path", "path", manualPath, "error", err)
+			return nil, nil
+		}
+
+		logger.Logger.Info("Manual WASM path provided by user", "path", manualPath, "documented_functions", len(doc.Functions))
+		return &SourceCode{
+			Repository: "manual:" + manualPath,
+			Docs:       doc.Functions,
+		}, nil
 	}
 
-	// 4. Cache the result
+	// 4. Annotate with function docs, preferring the verified WASM's
+	// contractspecv0 section and falling back to doc comments in the
+	// fetched Rust source.
+	source.Docs = r.extractDocs(contractID, source)
+
+	// 5. Cache the result
 	if r.cache != nil {
 		if err := r.cache.Put(source); err != nil {
 			logger.Logger.Warn("Failed to cache source", "contract_id", contractID, "error", err)
@@ -107,11 +120,36 @@ func (r *Resolver) Resolve(ctx context.Context, contractID string) (*SourceCode,
 		"contract_id", contractID,
 		"repository", source.Repository,
 		"file_count", len(source.Files),
+		"documented_functions", len(source.Docs),
 	)
 
 	return source, nil
 }
 
+// extractDocs builds the per-function doc map for a freshly fetched
+// SourceCode. It prefers the contractspecv0 section of the verified WASM
+// the registry returned alongside the source, since that's the same
+// data the manual-path fallback (LoadContractDocFromWasm) uses and is
+// authoritative for what's actually deployed; the `///` doc comments in
+// the fetched Rust source are only consulted when no WASM was returned,
+// it fails to parse, or it documents no functions.
+func (r *Resolver) extractDocs(contractID string, source *SourceCode) map[string]FunctionDoc {
+	if len(source.Wasm) > 0 {
+		doc, err := parseContractDoc(source.Wasm)
+		if err != nil {
+			logger.Logger.Debug("Failed to parse contractspecv0 from registry WASM, falling back to doc comments", "contract_id", contractID, "error", err)
+		} else if len(doc.Functions) > 0 {
+			return doc.Functions
+		}
+	}
+
+	doc := loadDocsFromSource(source.Files)
+	if len(doc.Functions) == 0 {
+		logger.Logger.Debug("No doc comments found in fetched source", "contract_id", contractID)
+	}
+	return doc.Functions
+}
+
 // PromptForWasmPath pauses execution and asks the user for a manual WASM path.
 // Requirement: If erst encounters an unknown contract, pause and ask the user 
 // "Please provide path to contract WASM for better mapping".
@@ -128,6 +166,22 @@ func (r *Resolver) PromptForWasmPath() (string, error) {
 	return strings.TrimSpace(path), nil
 }
 
+// Docs returns the cached per-function documentation for contractID, if
+// any has been resolved and cached already. Unlike Resolve, it never
+// hits the registry or prompts the user -- callers that only want to
+// annotate already-decoded data (e.g. cmd/stats) shouldn't pay for a
+// network round trip or a manual-path prompt just to look up a name.
+func (r *Resolver) Docs(contractID string) map[string]FunctionDoc {
+	if r.cache == nil {
+		return nil
+	}
+	cached := r.cache.Get(contractID)
+	if cached == nil {
+		return nil
+	}
+	return cached.Docs
+}
+
 // InvalidateCache removes a specific contract from the cache.
 func (r *Resolver) InvalidateCache(contractID string) error {
 	if r.cache == nil {
@@ -0,0 +1,26 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package sourcemap
+
+// SourceCode is the verified source and metadata resolved for one
+// contract, whether it came from RegistryClient.FetchVerifiedSource, a
+// cached SourceCache entry, or the manual-WASM-path fallback in
+// Resolve.
+type SourceCode struct {
+	// Repository identifies where the source came from: the registry's
+	// location for a verified lookup, or "manual:<path>" when the user
+	// supplied a WASM path directly via PromptForWasmPath.
+	Repository string
+	// Files holds each source file's contents, keyed by path.
+	Files map[string]string
+	// Wasm is the compiled contract binary the registry returned
+	// alongside Files, when available. extractDocs parses its
+	// contractspecv0 section for function docs before falling back to
+	// the `///` doc comments in Files.
+	Wasm []byte
+	// Docs is the per-function documentation extracted for this
+	// contract, keyed by function name. Populated by Resolve via
+	// extractDocs.
+	Docs map[string]FunctionDoc
+}
@@ -4,6 +4,9 @@
 package visualizer
 
 import (
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/sourcemap"
 	"github.com/dotandev/hintents/internal/terminal"
 )
 
@@ -38,3 +41,19 @@ func Error() string {
 func Symbol(name string) string {
 	return defaultRenderer.Symbol(name)
 }
+
+// AnnotateFunction renders a call-tree function name alongside its
+// resolved doc summary, e.g. "transfer (Moves amount from one account to
+// another)". If docs is nil or has no entry for name, the name is
+// returned unannotated -- most contracts won't have a cached doc for
+// every function, and that's not an error condition worth surfacing.
+func AnnotateFunction(name string, docs map[string]sourcemap.FunctionDoc) string {
+	if docs == nil {
+		return name
+	}
+	doc, ok := docs[name]
+	if !ok || doc.UserDoc == "" {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, doc.UserDoc)
+}